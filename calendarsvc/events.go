@@ -0,0 +1,24 @@
+package calendarsvc
+
+import (
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// GetEvents は、指定したカレンダーから [timeMin, timeMax) の範囲のイベントを取得する。
+// timeMax がゼロ値の場合は上限を設けない（従来の「これ以降すべて」の挙動）。
+func GetEvents(srv *calendar.Service, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	call := srv.Events.List(calendarID).ShowDeleted(false).
+		SingleEvents(true).OrderBy("startTime").TimeMin(timeMin.Format(time.RFC3339))
+
+	if !timeMax.IsZero() {
+		call = call.TimeMax(timeMax.Format(time.RFC3339))
+	}
+
+	events, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
@@ -0,0 +1,132 @@
+package calendarsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// UserTokenStore は、Discordユーザーごとに発行されたOAuthトークンを保存・取得する。
+// /add や /delete のようにユーザー自身のカレンダーを操作するコマンドで使う。
+type UserTokenStore interface {
+	// Token は、discordUserID に紐づくトークンを返す。未登録の場合は ok=false を返す。
+	Token(discordUserID string) (tok *oauth2.Token, ok bool, err error)
+	// SaveToken は、discordUserID に紐づくトークンを保存する
+	SaveToken(discordUserID string, tok *oauth2.Token) error
+}
+
+// JSONUserTokenStore は、Discordユーザー毎のOAuthトークンをローカルのJSONファイルに保存する
+// UserTokenStore実装。本番ではFirestore/Redisなど共有ストアに差し替えることを想定している。
+// 読み取り（Token）は書き込み（SaveToken）より頻繁なので、sync.RWMutexで
+// 複数ゴルーチンからの同時読み取りを許しつつ、書き込みは排他にしている。
+type JSONUserTokenStore struct {
+	path   string
+	mu     sync.RWMutex
+	tokens map[string]*oauth2.Token
+}
+
+// NewJSONUserTokenStore は、path にあるJSONファイルからトークンを読み込む。
+// ファイルが存在しない場合は空の状態から開始する。
+func NewJSONUserTokenStore(path string) (*JSONUserTokenStore, error) {
+	s := &JSONUserTokenStore{
+		path:   path,
+		tokens: make(map[string]*oauth2.Token),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.tokens); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *JSONUserTokenStore) Token(discordUserID string) (*oauth2.Token, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tok, ok := s.tokens[discordUserID]
+	return tok, ok, nil
+}
+
+func (s *JSONUserTokenStore) SaveToken(discordUserID string, tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[discordUserID] = tok
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+// NewUserService は、discordUserID に紐づくOAuthトークンを使ってGoogle Calendar API
+// クライアントを作成する。ユーザーがまだ認可フローを完了していない場合はエラーを返す。
+func NewUserService(ctx context.Context, cfg *oauth2.Config, tokens UserTokenStore, discordUserID string) (*calendar.Service, error) {
+	tok, ok, err := tokens.Token(discordUserID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load OAuth token for user %s: %w", discordUserID, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("user %s has not authorized calendar access yet", discordUserID)
+	}
+
+	src := &savingTokenSource{
+		discordUserID: discordUserID,
+		tokens:        tokens,
+		last:          tok,
+		src:           oauth2.ReuseTokenSource(tok, cfg.TokenSource(ctx, tok)),
+	}
+
+	srv, err := calendar.NewService(ctx, option.WithTokenSource(src))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Calendar client for user %s: %w", discordUserID, err)
+	}
+	return srv, nil
+}
+
+// savingTokenSource は、oauth2.TokenSource をラップし、アクセストークンが
+// リフレッシュ（リフレッシュトークンのローテーションを含む）されるたびに
+// その都度 tokens.SaveToken で永続化する。そうしないと、cfg.TokenSource が
+// メモリ上でリフレッシュしたトークンが保存先に反映されず、リクエストの
+// たびにGoogleのトークンエンドポイントへ再リフレッシュしに行ってしまい、
+// さらにGoogleがリフレッシュトークンを入れ替えた場合は保存済みのものが
+// 失効したまま復旧手段がなくなる。
+type savingTokenSource struct {
+	discordUserID string
+	tokens        UserTokenStore
+
+	mu   sync.Mutex
+	last *oauth2.Token
+	src  oauth2.TokenSource
+}
+
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last != nil && s.last.AccessToken == tok.AccessToken && s.last.RefreshToken == tok.RefreshToken {
+		return tok, nil
+	}
+	s.last = tok
+	if err := s.tokens.SaveToken(s.discordUserID, tok); err != nil {
+		return nil, fmt.Errorf("unable to persist refreshed OAuth token for user %s: %w", s.discordUserID, err)
+	}
+	return tok, nil
+}
@@ -0,0 +1,44 @@
+package calendarsvc
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// CalendarPool hands out *calendar.Service clients without ever sharing one
+// client as long-lived mutable state across goroutines: each caller borrows
+// a client via Get and returns it via Put when done, instead of holding a
+// single shared client in a struct field. All fields are set once at
+// construction and never mutated afterwards, so a *CalendarPool is itself
+// safe to share across goroutines (e.g. one per route in a multi-calendar
+// fan-out, or one per bot command handler).
+type CalendarPool struct {
+	newClient func(ctx context.Context) (*calendar.Service, error)
+	pool      sync.Pool
+}
+
+// NewCalendarPool builds a pool that lazily constructs clients via
+// newClient (e.g. NewServiceAccountService) and reuses idle ones instead of
+// reconstructing a client on every call.
+func NewCalendarPool(newClient func(ctx context.Context) (*calendar.Service, error)) *CalendarPool {
+	return &CalendarPool{newClient: newClient}
+}
+
+// Get returns an idle client from the pool, or constructs a fresh one via
+// newClient if none is idle.
+func (p *CalendarPool) Get(ctx context.Context) (*calendar.Service, error) {
+	if v := p.pool.Get(); v != nil {
+		return v.(*calendar.Service), nil
+	}
+	return p.newClient(ctx)
+}
+
+// Put returns a client to the pool for reuse by a future Get. Callers must
+// not use srv after calling Put.
+func (p *CalendarPool) Put(srv *calendar.Service) {
+	if srv != nil {
+		p.pool.Put(srv)
+	}
+}
@@ -0,0 +1,97 @@
+package calendarsvc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// TestCalendarPoolConcurrentGetPut spins up many goroutines that repeatedly
+// borrow and return a client, simulating a multi-calendar fan-out where
+// every route pulls its own client. Run with `go test -race` to prove no
+// client (or the pool's internal state) is ever shared unsafely.
+func TestCalendarPoolConcurrentGetPut(t *testing.T) {
+	const goroutines = 20
+	const iterations = 50
+
+	var constructed int64
+	pool := NewCalendarPool(func(ctx context.Context) (*calendar.Service, error) {
+		atomic.AddInt64(&constructed, 1)
+		return &calendar.Service{}, nil
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				srv, err := pool.Get(context.Background())
+				if err != nil {
+					t.Errorf("Get failed: %v", err)
+					return
+				}
+				if srv == nil {
+					t.Errorf("Get returned a nil client")
+					return
+				}
+				pool.Put(srv)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt64(&constructed) == 0 {
+		t.Fatal("expected at least one client to be constructed")
+	}
+}
+
+// TestCalendarPoolConstructsOnlyWhenIdle verifies Get never hands out a
+// client that is concurrently in use: it keeps borrowed clients around for
+// the duration of a simulated "request" before returning them.
+func TestCalendarPoolConstructsOnlyWhenIdle(t *testing.T) {
+	var constructed int64
+	pool := NewCalendarPool(func(ctx context.Context) (*calendar.Service, error) {
+		atomic.AddInt64(&constructed, 1)
+		return &calendar.Service{}, nil
+	})
+
+	const calendars = 5
+	var (
+		mu       sync.Mutex
+		inUse    = make(map[*calendar.Service]bool)
+		borrowed sync.WaitGroup
+		wg       sync.WaitGroup
+	)
+	borrowed.Add(calendars)
+	for c := 0; c < calendars; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv, err := pool.Get(context.Background())
+			if err != nil {
+				t.Errorf("Get failed: %v", err)
+				borrowed.Done()
+				return
+			}
+			defer pool.Put(srv)
+			mu.Lock()
+			if inUse[srv] {
+				t.Errorf("Get handed out a client that is already borrowed")
+			}
+			inUse[srv] = true
+			mu.Unlock()
+			borrowed.Done()
+			borrowed.Wait()  // hold the client until every goroutine has borrowed one
+			_ = srv.BasePath // touch the borrowed client like a real caller would
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&constructed); got != calendars {
+		t.Errorf("constructed %d clients for %d simultaneous borrowers, want %d", got, calendars, calendars)
+	}
+}
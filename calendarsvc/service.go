@@ -0,0 +1,39 @@
+// Package calendarsvc wraps Google Calendar client construction so that both
+// the cron notifier and the Discord bot can obtain a *calendar.Service,
+// either via a shared service account or via a per-Discord-user OAuth token.
+package calendarsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// CalendarScope は、OAuthユーザーフローで要求するGoogle Calendarのスコープ
+const CalendarScope = "https://www.googleapis.com/auth/calendar"
+
+// NewServiceAccountService は、GOOGLE_CREDENTIALS環境変数のサービスアカウント鍵を
+// 使ってGoogle Calendar APIクライアントを作成する。
+func NewServiceAccountService(ctx context.Context) (*calendar.Service, error) {
+	credentials := os.Getenv("GOOGLE_CREDENTIALS")
+	if credentials == "" {
+		return nil, fmt.Errorf("GOOGLE_CREDENTIALS environment variable not set")
+	}
+
+	creds := []byte(credentials)
+	config := make(map[string]interface{})
+	if err := json.Unmarshal(creds, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse GOOGLE_CREDENTIALS: %v", err)
+	}
+
+	srv, err := calendar.NewService(ctx, option.WithCredentialsJSON(creds))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Calendar client: %v", err)
+	}
+
+	return srv, nil
+}
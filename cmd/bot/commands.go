@@ -0,0 +1,67 @@
+package main
+
+import "github.com/bwmarrin/discordgo"
+
+// commands は、起動時に登録するスラッシュコマンドの定義一覧
+var commands = []*discordgo.ApplicationCommand{
+	{
+		Name:        "today",
+		Description: "今から24時間以内に開催されるイベントの一覧を表示します",
+	},
+	{
+		Name:        "week",
+		Description: "今から7日以内に開催されるイベントの一覧を表示します",
+	},
+	{
+		Name:        "next",
+		Description: "次に開催されるイベントを表示します",
+	},
+	{
+		Name:        "add",
+		Description: "自分のGoogleカレンダーに予定を追加します（初回は認可が必要です）",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "summary",
+				Description: "予定のタイトル",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始日時（RFC3339、例: 2026-08-01T21:00:00+09:00）",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "end",
+				Description: "終了日時（RFC3339）",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "schedule",
+		Description: "自然文から予定を読み取り、確認のうえ自分のGoogleカレンダーに追加します",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "text",
+				Description: "例: raid friday 9pm 2h @alice @bob / 飲み会 明日21時 2h",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "delete",
+		Description: "自分のGoogleカレンダーから予定を削除します（初回は認可が必要です）",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "id",
+				Description: "削除するイベントのID（/today や /week の結果に含まれます）",
+				Required:    true,
+			},
+		},
+	},
+}
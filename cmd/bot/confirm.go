@@ -0,0 +1,47 @@
+package main
+
+import (
+	"sync"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// pendingSchedule is an event parsed from /schedule that is awaiting the
+// requesting user's ✅/❌ reaction before it is actually inserted.
+type pendingSchedule struct {
+	event  *calendar.Event
+	userID string
+}
+
+// pendingScheduleStore keeps pendingSchedule entries keyed by the Discord
+// message ID that shows the confirmation prompt, so onMessageReactionAdd can
+// look one up by the message being reacted to.
+type pendingScheduleStore struct {
+	mu      sync.Mutex
+	pending map[string]pendingSchedule
+}
+
+func newPendingScheduleStore() *pendingScheduleStore {
+	return &pendingScheduleStore{pending: make(map[string]pendingSchedule)}
+}
+
+// add registers a pending schedule under messageID.
+func (s *pendingScheduleStore) add(messageID string, p pendingSchedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[messageID] = p
+}
+
+// takeFor returns the pending schedule for messageID if it was requested by
+// userID, removing it from the store so a message can only be confirmed or
+// cancelled once.
+func (s *pendingScheduleStore) takeFor(messageID, userID string) (pendingSchedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pending[messageID]
+	if !ok || p.userID != userID {
+		return pendingSchedule{}, false
+	}
+	delete(s.pending, messageID)
+	return p, true
+}
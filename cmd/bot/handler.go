@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/GoWorkshopConference/reminder-for-discord/calendarsvc"
+	"github.com/GoWorkshopConference/reminder-for-discord/schedule"
+)
+
+const (
+	confirmEmoji = "✅"
+	cancelEmoji  = "❌"
+)
+
+// handler は、スラッシュコマンドの実行に必要な依存をまとめたもの
+type handler struct {
+	calendarPool *calendarsvc.CalendarPool
+	calendarID   string
+	oauthCfg     *oauth2.Config
+	tokens       calendarsvc.UserTokenStore
+	oauthStates  *oauthStateStore
+	location     *time.Location
+	pending      *pendingScheduleStore
+}
+
+// authURL は、discordUserID向けの認可URLを発行する。stateにはdiscordUserIDを
+// 直接埋め込まず、oauthStatesが発行したワンタイムnonceを使うことで、第三者が
+// 被害者のDiscordユーザーIDを知っているだけで自分のGoogleアカウントを被害者の
+// IDに結びつけてしまう攻撃を防ぐ。
+func (h *handler) authURL(discordUserID string) (string, error) {
+	state, err := h.oauthStates.New(discordUserID)
+	if err != nil {
+		return "", fmt.Errorf("unable to issue OAuth state: %w", err)
+	}
+	return h.oauthCfg.AuthCodeURL(state, oauth2.AccessTypeOffline), nil
+}
+
+func (h *handler) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	var err error
+	switch data.Name {
+	case "today":
+		err = h.replyEventList(s, i, time.Now(), time.Now().Add(24*time.Hour))
+	case "week":
+		err = h.replyEventList(s, i, time.Now(), time.Now().Add(7*24*time.Hour))
+	case "next":
+		err = h.replyNextEvent(s, i)
+	case "add":
+		err = h.handleAdd(s, i, data)
+	case "schedule":
+		err = h.handleSchedule(s, i, data)
+	case "delete":
+		err = h.handleDelete(s, i, data)
+	default:
+		err = fmt.Errorf("unknown command: %s", data.Name)
+	}
+
+	if err != nil {
+		respondError(s, i, err)
+	}
+}
+
+// replyEventList は、[from, to) の範囲のイベント一覧をembedとして返信する
+func (h *handler) replyEventList(s *discordgo.Session, i *discordgo.InteractionCreate, from, to time.Time) error {
+	srv, err := h.calendarPool.Get(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve Calendar client: %w", err)
+	}
+	defer h.calendarPool.Put(srv)
+
+	events, err := calendarsvc.GetEvents(srv, h.calendarID, from, to)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve events: %w", err)
+	}
+
+	embeds := make([]*discordgo.MessageEmbed, 0, len(events))
+	for _, event := range events {
+		embeds = append(embeds, eventEmbed(event))
+	}
+	if len(embeds) == 0 {
+		return respondContent(s, i, "該当する期間の予定はありません。")
+	}
+	return respondEmbeds(s, i, embeds)
+}
+
+// replyNextEvent は、直近のイベント1件をembedとして返信する
+func (h *handler) replyNextEvent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	srv, err := h.calendarPool.Get(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve Calendar client: %w", err)
+	}
+	defer h.calendarPool.Put(srv)
+
+	events, err := calendarsvc.GetEvents(srv, h.calendarID, time.Now(), time.Time{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve events: %w", err)
+	}
+	if len(events) == 0 {
+		return respondContent(s, i, "今後の予定はありません。")
+	}
+	return respondEmbeds(s, i, []*discordgo.MessageEmbed{eventEmbed(events[0])})
+}
+
+// handleAdd は、/add コマンドを処理する。ユーザーがまだOAuth認可を済ませていない場合は
+// 認可URLを案内する。
+func (h *handler) handleAdd(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) error {
+	userID := interactionUserID(i)
+	srv, err := calendarsvc.NewUserService(context.Background(), h.oauthCfg, h.tokens, userID)
+	if err != nil {
+		authURL, authErr := h.authURL(userID)
+		if authErr != nil {
+			return authErr
+		}
+		return respondContent(s, i, fmt.Sprintf(
+			"カレンダーへのアクセスがまだ認可されていません。こちらのURLから認可してください:\n%s",
+			authURL))
+	}
+
+	opts := optionMap(data)
+	start, err := time.Parse(time.RFC3339, opts["start"].StringValue())
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, opts["end"].StringValue())
+	if err != nil {
+		return fmt.Errorf("invalid end time: %w", err)
+	}
+
+	event := &calendar.Event{
+		Summary: opts["summary"].StringValue(),
+		Start:   &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+		End:     &calendar.EventDateTime{DateTime: end.Format(time.RFC3339)},
+	}
+
+	created, err := srv.Events.Insert(h.calendarID, event).Do()
+	if err != nil {
+		return fmt.Errorf("unable to create event: %w", err)
+	}
+
+	return respondEmbeds(s, i, []*discordgo.MessageEmbed{eventEmbed(created)})
+}
+
+// handleSchedule は、/schedule コマンドを処理する。自然文から予定を読み取り、
+// 確認用のembedにリアクションを付与したうえで、ユーザーが✅を押すまでは
+// カレンダーへの登録を行わない。
+func (h *handler) handleSchedule(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) error {
+	opts := optionMap(data)
+	text := opts["text"].StringValue()
+
+	p, err := schedule.Parse(text, time.Now().In(h.location), h.location)
+	if err != nil {
+		return respondContent(s, i, fmt.Sprintf("予定を読み取れませんでした: %v", err))
+	}
+	event := schedule.ToCalendarEvent(p, h.location)
+
+	embed := eventEmbed(event)
+	embed.Description = fmt.Sprintf("%s で登録、%s でキャンセルします。", confirmEmoji, cancelEmoji)
+
+	if err := respondEmbeds(s, i, []*discordgo.MessageEmbed{embed}); err != nil {
+		return err
+	}
+
+	msg, err := s.InteractionResponse(i.Interaction)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve confirmation message: %w", err)
+	}
+
+	userID := interactionUserID(i)
+	h.pending.add(msg.ID, pendingSchedule{event: event, userID: userID})
+
+	if err := s.MessageReactionAdd(msg.ChannelID, msg.ID, confirmEmoji); err != nil {
+		return fmt.Errorf("unable to add confirmation reaction: %w", err)
+	}
+	if err := s.MessageReactionAdd(msg.ChannelID, msg.ID, cancelEmoji); err != nil {
+		return fmt.Errorf("unable to add cancellation reaction: %w", err)
+	}
+
+	return nil
+}
+
+// onMessageReactionAdd は、/schedule の確認embedに対する✅・❌のリアクションを
+// 処理する。リアクションしたのが /schedule を実行した本人でない場合や、
+// 対象のメッセージに確認待ちの予定がない場合は何もしない。
+func (h *handler) onMessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if r.UserID == s.State.User.ID {
+		return
+	}
+
+	switch r.Emoji.Name {
+	case confirmEmoji:
+		h.confirmSchedule(s, r)
+	case cancelEmoji:
+		h.cancelSchedule(s, r)
+	}
+}
+
+func (h *handler) confirmSchedule(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	p, ok := h.pending.takeFor(r.MessageID, r.UserID)
+	if !ok {
+		return
+	}
+
+	srv, err := calendarsvc.NewUserService(context.Background(), h.oauthCfg, h.tokens, r.UserID)
+	if err != nil {
+		authURL, authErr := h.authURL(r.UserID)
+		if authErr != nil {
+			log.Printf("unable to issue OAuth state for user %s: %v", r.UserID, authErr)
+			s.ChannelMessageSend(r.ChannelID, "カレンダーへのアクセスの認可URLを発行できませんでした。しばらくしてからもう一度お試しください。")
+			return
+		}
+		s.ChannelMessageSend(r.ChannelID, fmt.Sprintf(
+			"カレンダーへのアクセスがまだ認可されていません。こちらのURLから認可してください:\n%s",
+			authURL))
+		return
+	}
+
+	created, err := srv.Events.Insert(h.calendarID, p.event).Do()
+	if err != nil {
+		s.ChannelMessageSend(r.ChannelID, fmt.Sprintf("予定の登録に失敗しました: %v", err))
+		return
+	}
+
+	s.ChannelMessageSendEmbed(r.ChannelID, eventEmbed(created))
+}
+
+func (h *handler) cancelSchedule(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if _, ok := h.pending.takeFor(r.MessageID, r.UserID); !ok {
+		return
+	}
+	s.ChannelMessageSend(r.ChannelID, "予定の登録をキャンセルしました。")
+}
+
+// handleDelete は、/delete コマンドを処理する
+func (h *handler) handleDelete(s *discordgo.Session, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) error {
+	userID := interactionUserID(i)
+	srv, err := calendarsvc.NewUserService(context.Background(), h.oauthCfg, h.tokens, userID)
+	if err != nil {
+		authURL, authErr := h.authURL(userID)
+		if authErr != nil {
+			return authErr
+		}
+		return respondContent(s, i, fmt.Sprintf(
+			"カレンダーへのアクセスがまだ認可されていません。こちらのURLから認可してください:\n%s",
+			authURL))
+	}
+
+	opts := optionMap(data)
+	eventID := opts["id"].StringValue()
+	if err := srv.Events.Delete(h.calendarID, eventID).Do(); err != nil {
+		return fmt.Errorf("unable to delete event %s: %w", eventID, err)
+	}
+
+	return respondContent(s, i, fmt.Sprintf("イベント %s を削除しました。", eventID))
+}
+
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+func optionMap(data discordgo.ApplicationCommandInteractionData) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	opts := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(data.Options))
+	for _, opt := range data.Options {
+		opts[opt.Name] = opt
+	}
+	return opts
+}
@@ -0,0 +1,108 @@
+// Command bot runs reminder-for-discord as a long-running Discord bot,
+// registering slash commands instead of relying on a one-shot cron invocation.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/GoWorkshopConference/reminder-for-discord/calendarsvc"
+)
+
+func main() {
+	token := os.Getenv("DISCORD_BOT_TOKEN")
+	if token == "" {
+		log.Fatal("DISCORD_BOT_TOKEN environment variable not set")
+	}
+	guildID := os.Getenv("DISCORD_GUILD_ID") // 空文字列ならグローバルコマンドとして登録
+
+	ctx := context.Background()
+
+	// CalendarPoolは、複数のインタラクションを並行に処理しても1つの共有クライアントを
+	// 奪い合わないよう、コマンドハンドラごとに自分専用のクライアントを借りられるようにする。
+	calendarPool := calendarsvc.NewCalendarPool(calendarsvc.NewServiceAccountService)
+	warmClient, err := calendarPool.Get(ctx)
+	if err != nil {
+		log.Fatalf("Unable to retrieve service-account Calendar client: %v", err)
+	}
+	calendarPool.Put(warmClient)
+
+	tokens, err := newUserTokenStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Unable to open OAuth token store: %v", err)
+	}
+
+	oauthCfg, err := oauthConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Unable to build OAuth config: %v", err)
+	}
+
+	callbackAddr := os.Getenv("OAUTH_CALLBACK_ADDR")
+	if callbackAddr == "" {
+		callbackAddr = defaultOAuthCallbackAddr
+	}
+	oauthStates := newOAuthStateStore()
+	startOAuthCallbackServer(callbackAddr, oauthCfg, tokens, oauthStates)
+
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		log.Fatalf("Unable to create Discord session: %v", err)
+	}
+
+	h := &handler{
+		calendarPool: calendarPool,
+		calendarID:   os.Getenv("GOOGLE_CALENDAR_ID"),
+		oauthCfg:     oauthCfg,
+		tokens:       tokens,
+		oauthStates:  oauthStates,
+		location:     time.FixedZone("Asia/Tokyo", 9*60*60),
+		pending:      newPendingScheduleStore(),
+	}
+	session.AddHandler(h.onInteractionCreate)
+	session.AddHandler(h.onMessageReactionAdd)
+
+	if err := session.Open(); err != nil {
+		log.Fatalf("Unable to open Discord session: %v", err)
+	}
+	defer session.Close()
+
+	registered, err := session.ApplicationCommandBulkOverwrite(session.State.User.ID, guildID, commands)
+	if err != nil {
+		log.Fatalf("Unable to register slash commands: %v", err)
+	}
+	log.Printf("Registered %d slash commands", len(registered))
+
+	log.Println("Bot is running. Press Ctrl+C to exit.")
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+}
+
+// oauthConfigFromEnv は、/add・/delete が利用するGoogle OAuthクライアント設定を
+// 環境変数から組み立てる
+func oauthConfigFromEnv() (*oauth2.Config, error) {
+	return &oauth2.Config{
+		ClientID:     os.Getenv("GOOGLE_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOOGLE_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("GOOGLE_OAUTH_REDIRECT_URL"),
+		Endpoint:     google.Endpoint,
+		Scopes:       []string{calendarsvc.CalendarScope},
+	}, nil
+}
+
+// newUserTokenStoreFromEnv は、Discordユーザー毎のOAuthトークンを保存するストアを開く
+func newUserTokenStoreFromEnv() (*calendarsvc.JSONUserTokenStore, error) {
+	path := os.Getenv("OAUTH_TOKEN_STORE_PATH")
+	if path == "" {
+		path = "user_tokens.json"
+	}
+	return calendarsvc.NewJSONUserTokenStore(path)
+}
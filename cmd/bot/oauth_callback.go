@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/GoWorkshopConference/reminder-for-discord/calendarsvc"
+)
+
+// oauthCallbackPath は、GOOGLE_OAUTH_REDIRECT_URL が指すべきパス。
+// handleAdd/handleDelete/confirmSchedule が案内する認可URLのリダイレクト先として
+// Google Cloud Console側にも同じパスで登録しておく必要がある。
+const oauthCallbackPath = "/oauth2callback"
+
+// defaultOAuthCallbackAddr は、OAUTH_CALLBACK_ADDR が未設定の場合に使う既定のbindアドレス。
+const defaultOAuthCallbackAddr = ":8089"
+
+// startOAuthCallbackServer は、Googleの認可画面からのリダイレクトを受け取り、
+// 認可コードをトークンに交換してtokensに保存するHTTPサーバーをバックグラウンドで起動する。
+// これがないと AuthCodeURL を案内するだけで誰のトークンも保存されず、/add・/delete・
+// /schedule は永遠に未認可のままになってしまう。
+func startOAuthCallbackServer(addr string, cfg *oauth2.Config, tokens calendarsvc.UserTokenStore, states *oauthStateStore) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(oauthCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		handleOAuthCallback(w, r, cfg, tokens, states)
+	})
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Fatalf("OAuth callback server stopped: %v", err)
+		}
+	}()
+	log.Printf("OAuth callback server listening on %s%s", addr, oauthCallbackPath)
+}
+
+// handleOAuthCallback は、state パラメータに載せたワンタイムnonceをoauthStateStoreで
+// 検証してDiscordユーザーIDを復元したうえで、認可コードを交換し、得られたトークンを
+// 保存する。state をDiscordユーザーIDそのものとして信用すると、第三者が被害者の
+// DiscordユーザーIDを知っているだけで自分のGoogleアカウントを被害者のIDに結びつけ
+// られてしまうため、必ずstatesで発行・検証したnonce経由でのみ受け付ける。
+func handleOAuthCallback(w http.ResponseWriter, r *http.Request, cfg *oauth2.Config, tokens calendarsvc.UserTokenStore, states *oauthStateStore) {
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+	if code == "" || state == "" {
+		http.Error(w, "missing code or state parameter", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := states.Consume(state)
+	if !ok {
+		http.Error(w, "invalid or expired state parameter", http.StatusBadRequest)
+		return
+	}
+
+	tok, err := cfg.Exchange(r.Context(), code)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to exchange code: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := tokens.SaveToken(userID, tok); err != nil {
+		http.Error(w, fmt.Sprintf("failed to save token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprint(w, "カレンダーへのアクセスを認可しました。Discordに戻って操作を続けてください。")
+}
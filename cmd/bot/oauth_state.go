@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// oauthStateTTL は、発行したstateを受け付ける猶予期間。
+// ユーザーが認可画面で長時間迷うことはあっても、攻撃者が後から流用できる
+// 時間は短く保つ。
+const oauthStateTTL = 10 * time.Minute
+
+// oauthStateStore は、AuthCodeURLのstateパラメータとして発行したワンタイムの
+// ランダムなnonceを、それを発行した対象のDiscordユーザーIDに紐づけて保持する。
+// stateがDiscordユーザーIDそのものだと、攻撃者が被害者のIDを知っているだけで
+// 自分のGoogleアカウントを被害者のDiscordユーザーIDに結びつけられてしまう
+// （state固定によるアカウント紐付け攻撃）ため、推測不能なnonceを経由させる。
+type oauthStateStore struct {
+	mu      sync.Mutex
+	entries map[string]oauthStateEntry
+}
+
+type oauthStateEntry struct {
+	discordUserID string
+	expiresAt     time.Time
+}
+
+func newOAuthStateStore() *oauthStateStore {
+	return &oauthStateStore{entries: make(map[string]oauthStateEntry)}
+}
+
+// New は、discordUserID向けの新しいstateトークンを発行する。
+func (s *oauthStateStore) New(discordUserID string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("unable to generate OAuth state: %w", err)
+	}
+	state := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gcLocked()
+	s.entries[state] = oauthStateEntry{discordUserID: discordUserID, expiresAt: time.Now().Add(oauthStateTTL)}
+	return state, nil
+}
+
+// Consume は、stateに紐づくDiscordユーザーIDを返し、そのstateを使用済みとして
+// 破棄する。stateが未発行・期限切れ・既に使用済みの場合はok=falseを返す。
+func (s *oauthStateStore) Consume(state string) (discordUserID string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, found := s.entries[state]
+	if !found {
+		return "", false
+	}
+	delete(s.entries, state)
+	if time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.discordUserID, true
+}
+
+// gcLocked は、期限切れのstateを取り除く。呼び出し元でロック済みであること。
+func (s *oauthStateStore) gcLocked() {
+	now := time.Now()
+	for state, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, state)
+		}
+	}
+}
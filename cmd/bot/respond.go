@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"google.golang.org/api/calendar/v3"
+)
+
+// eventEmbed は、カレンダーイベントをDiscordのembedに変換する
+func eventEmbed(event *calendar.Event) *discordgo.MessageEmbed {
+	start, end := event.Start.DateTime, event.End.DateTime
+	if start == "" {
+		start, end = event.Start.Date+" (終日)", event.End.Date+" (終日)"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: event.Summary,
+		URL:   event.HtmlLink,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "開始", Value: start, Inline: true},
+			{Name: "終了", Value: end, Inline: true},
+			{Name: "場所", Value: valueOrDash(event.Location), Inline: false},
+		},
+	}
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func respondContent(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: content},
+	})
+}
+
+func respondEmbeds(s *discordgo.Session, i *discordgo.InteractionCreate, embeds []*discordgo.MessageEmbed) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Embeds: embeds},
+	})
+}
+
+func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, err error) {
+	_ = respondContent(s, i, fmt.Sprintf("エラーが発生しました: %v", err))
+}
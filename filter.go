@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// FilterConfig は、設定ファイル上でのFilterの表現。コンパイル前の正規表現文字列を保持する。
+type FilterConfig struct {
+	SummaryRegex   string   `json:"summary_regex"`
+	LocationRegex  string   `json:"location_regex"`
+	AttendeeEmails []string `json:"attendee_emails"`
+	Color          string   `json:"color"`
+}
+
+// Filter は、ルートに流すイベントを絞り込むための述語。ゼロ値（全フィールド空）は
+// 「すべてのイベントにマッチする」ことを意味する。
+type Filter struct {
+	summaryRegex   *regexp.Regexp
+	locationRegex  *regexp.Regexp
+	attendeeEmails map[string]bool
+	color          string
+}
+
+// Compile は、FilterConfigの正規表現をコンパイルしてFilterを組み立てる
+func (c FilterConfig) Compile() (Filter, error) {
+	var f Filter
+
+	if c.SummaryRegex != "" {
+		re, err := regexp.Compile(c.SummaryRegex)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid summary_regex %q: %w", c.SummaryRegex, err)
+		}
+		f.summaryRegex = re
+	}
+	if c.LocationRegex != "" {
+		re, err := regexp.Compile(c.LocationRegex)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid location_regex %q: %w", c.LocationRegex, err)
+		}
+		f.locationRegex = re
+	}
+	if len(c.AttendeeEmails) > 0 {
+		f.attendeeEmails = make(map[string]bool, len(c.AttendeeEmails))
+		for _, email := range c.AttendeeEmails {
+			f.attendeeEmails[email] = true
+		}
+	}
+	f.color = c.Color
+
+	return f, nil
+}
+
+// Match は、イベントがこのFilterの全条件を満たすかどうかを返す
+func (f Filter) Match(event *calendar.Event) bool {
+	if f.summaryRegex != nil && !f.summaryRegex.MatchString(event.Summary) {
+		return false
+	}
+	if f.locationRegex != nil && !f.locationRegex.MatchString(event.Location) {
+		return false
+	}
+	if f.color != "" && event.ColorId != f.color {
+		return false
+	}
+	if len(f.attendeeEmails) > 0 {
+		matched := false
+		for _, attendee := range event.Attendees {
+			if f.attendeeEmails[attendee.Email] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
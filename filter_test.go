@@ -0,0 +1,113 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestFilterCompileInvalidRegex(t *testing.T) {
+	if _, err := (FilterConfig{SummaryRegex: "("}).Compile(); err == nil {
+		t.Fatal("Compile with an invalid summary_regex returned no error")
+	}
+	if _, err := (FilterConfig{LocationRegex: "("}).Compile(); err == nil {
+		t.Fatal("Compile with an invalid location_regex returned no error")
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		config FilterConfig
+		event  *calendar.Event
+		want   bool
+	}{
+		{
+			name:   "zero value matches everything",
+			config: FilterConfig{},
+			event:  &calendar.Event{Summary: "anything"},
+			want:   true,
+		},
+		{
+			name:   "summary regex matches",
+			config: FilterConfig{SummaryRegex: "^raid"},
+			event:  &calendar.Event{Summary: "raid night"},
+			want:   true,
+		},
+		{
+			name:   "summary regex does not match",
+			config: FilterConfig{SummaryRegex: "^raid"},
+			event:  &calendar.Event{Summary: "guild meeting"},
+			want:   false,
+		},
+		{
+			name:   "location regex matches",
+			config: FilterConfig{LocationRegex: "Tokyo"},
+			event:  &calendar.Event{Location: "Tokyo Office"},
+			want:   true,
+		},
+		{
+			name:   "location regex does not match",
+			config: FilterConfig{LocationRegex: "Tokyo"},
+			event:  &calendar.Event{Location: "Osaka Office"},
+			want:   false,
+		},
+		{
+			name:   "color matches",
+			config: FilterConfig{Color: "5"},
+			event:  &calendar.Event{ColorId: "5"},
+			want:   true,
+		},
+		{
+			name:   "color does not match",
+			config: FilterConfig{Color: "5"},
+			event:  &calendar.Event{ColorId: "1"},
+			want:   false,
+		},
+		{
+			name:   "attendee email matches",
+			config: FilterConfig{AttendeeEmails: []string{"a@example.com", "b@example.com"}},
+			event: &calendar.Event{Attendees: []*calendar.EventAttendee{
+				{Email: "c@example.com"},
+				{Email: "b@example.com"},
+			}},
+			want: true,
+		},
+		{
+			name:   "attendee email does not match",
+			config: FilterConfig{AttendeeEmails: []string{"a@example.com"}},
+			event: &calendar.Event{Attendees: []*calendar.EventAttendee{
+				{Email: "c@example.com"},
+			}},
+			want: false,
+		},
+		{
+			name: "all conditions must match",
+			config: FilterConfig{
+				SummaryRegex:   "^raid",
+				LocationRegex:  "Tokyo",
+				AttendeeEmails: []string{"a@example.com"},
+				Color:          "5",
+			},
+			event: &calendar.Event{
+				Summary:   "raid night",
+				Location:  "Osaka Office",
+				ColorId:   "5",
+				Attendees: []*calendar.EventAttendee{{Email: "a@example.com"}},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.config.Compile()
+			if err != nil {
+				t.Fatalf("Compile returned error: %v", err)
+			}
+			if got := f.Match(tt.event); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
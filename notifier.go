@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoWorkshopConference/reminder-for-discord/calendarsvc"
+	"github.com/GoWorkshopConference/reminder-for-discord/notify"
+	"google.golang.org/api/calendar/v3"
+)
+
+// gcMaxAge は、GCMaxAgeが未設定の場合に使う既定のGC保持期間
+const gcMaxAge = 30 * 24 * time.Hour
+
+// defaultTickInterval は、TickIntervalが未設定の場合に使う既定のtick幅。
+// cron実行の間隔より十分広くとることで、実行の取りこぼしによる未通知を防ぐ。
+const defaultTickInterval = time.Hour
+
+// MentionNone は、Route.ChannelMention / Notifier.MentionOverride に指定することで、
+// ルールがMentionRoleを持っていてもそのルートではメンションを付与しないことを表す
+// 特別な値。ChannelMentionの空文字列は「オーバーライドしない（ルールの値をそのまま
+// 使う）」という意味にすでに使われているため、「メンションなし」を表すには区別できる
+// 値が必要になる。
+const MentionNone = "none"
+
+// eventData は、カレンダーイベントからルール評価に必要な情報だけを取り出したもの
+type eventData struct {
+	ID       string
+	Updated  string
+	Summary  string
+	Location string
+	Start    time.Time
+	End      time.Time
+	IsAllDay bool
+	// Raw は、embed/ICS生成など追加情報（主催者・参加者・会議リンク・繰り返し
+	// ルールなど）が必要な処理のために保持する、元のカレンダーイベント。
+	Raw *calendar.Event
+}
+
+// Notifier は、カレンダーからイベントを取得し、未通知のものだけDiscordに送信する。
+// Store を差し替えられるようにすることで、テストではメモリ上の実装を注入できる。
+type Notifier struct {
+	Calendar        *calendar.Service
+	CalendarID      string
+	WebhookURL      string
+	Location        *time.Location
+	Store           Store
+	ResendIfUpdated bool
+
+	// Rules は、評価するリマインダールール群。空の場合はDefaultReminderRules()が使われる。
+	Rules []ReminderRule
+	// TickInterval は、1回のRunが受け持つ時間窓の幅。
+	// cronの実行間隔以上に設定することで、2回の実行の間に通知タイミングが
+	// すり抜けてしまうのを防ぐ。
+	TickInterval time.Duration
+
+	// Filter は、通知対象とするイベントを絞り込む。ゼロ値はすべてのイベントにマッチする。
+	Filter Filter
+	// RouteKey は、同じStoreを複数のRouteで共有する際に重複排除の記録が混ざらない
+	// ようにするための、Route単位の識別子。空の場合はCalendarID+WebhookURLを使う。
+	RouteKey string
+	// MentionOverride が空でない場合、各ルールのMentionRoleの代わりにこちらを使う。
+	// マルチチャンネル配信で、ルート（チャンネル）ごとにメンション先を変えるために使う。
+	// MentionNone を指定すると、ルールがMentionRoleを持っていてもメンションなしにする。
+	MentionOverride string
+
+	// SecondaryLocation は、embedの開始・終了時刻に併記する2つ目のタイムゾーン。
+	// 未設定の場合はUTCを使う。
+	SecondaryLocation *time.Location
+
+	// GCMaxAge は、これより古い通知記録をGCで削除する閾値。0以下の場合はgcMaxAgeを使う。
+	GCMaxAge time.Duration
+
+	// MaxDiscordRetries は、Discordから429を受け取った場合の最大リトライ回数。
+	// 0以下の場合はsendDiscordNotificationの既定値を使う。
+	MaxDiscordRetries int
+}
+
+// Run は、各ルールについて「イベント開始時刻 - Offset」が今回のtick窓に入っている
+// イベントを走査し、未通知のものについて通知を送信する。
+// 同じ (event.Id, rule) の組み合わせでは一度しか通知しない。
+func (n *Notifier) Run(ctx context.Context) error {
+	rules := n.Rules
+	if len(rules) == 0 {
+		rules = DefaultReminderRules()
+	}
+	tick := n.TickInterval
+	if tick <= 0 {
+		tick = defaultTickInterval
+	}
+
+	now := time.Now().In(n.Location)
+	windowEnd := now.Add(tick)
+
+	events, err := calendarsvc.GetEvents(n.Calendar, n.CalendarID, now, time.Time{})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve events: %w", err)
+	}
+
+	maxLookahead := maxOffset(rules) + tick
+	for _, event := range events {
+		if !n.Filter.Match(event) {
+			continue
+		}
+
+		data, ok := toEventData(event, n.Location)
+		if !ok {
+			log.Printf("No valid start time found for event: %s", event.Summary)
+			continue
+		}
+
+		if data.Start.After(now.Add(maxLookahead)) {
+			continue
+		}
+
+		for _, rule := range rules {
+			target := data.Start.Add(-rule.Offset)
+			if target.Before(now) || !target.Before(windowEnd) {
+				continue
+			}
+
+			if err := n.fire(rule, data); err != nil {
+				log.Printf("Error sending Discord notification for event %s: %v", data.ID, err)
+			}
+		}
+	}
+
+	maxAge := n.GCMaxAge
+	if maxAge <= 0 {
+		maxAge = gcMaxAge
+	}
+	if err := n.Store.GC(maxAge); err != nil {
+		log.Printf("Error running notification store GC: %v", err)
+	}
+
+	return nil
+}
+
+// fire は、重複チェックのうえでルールに従った通知を送信し、送信結果をStoreへ記録する
+func (n *Notifier) fire(rule ReminderRule, data eventData) error {
+	notified, err := n.Store.Has(n.routeKey(), data.ID, rule.Kind(), data.Updated, n.ResendIfUpdated)
+	if err != nil {
+		return fmt.Errorf("unable to check notification state: %w", err)
+	}
+	if notified {
+		log.Println("Already notified, skipping event:", data.Summary)
+		return nil
+	}
+
+	switch n.MentionOverride {
+	case "":
+		// オーバーライドなし。ルール自身のMentionRoleをそのまま使う。
+	case MentionNone:
+		rule.MentionRole = ""
+	default:
+		rule.MentionRole = n.MentionOverride
+	}
+	message, err := rule.Render(data)
+	if err != nil {
+		return err
+	}
+
+	secondary := n.SecondaryLocation
+	if secondary == nil {
+		secondary = time.UTC
+	}
+	embed := notify.BuildEmbed(data.Raw, data.Start, data.End, data.IsAllDay, n.Location, secondary)
+	ics := notify.BuildICS(data.Raw, data.Start, data.End, data.IsAllDay)
+
+	if err := sendDiscordNotification(n.WebhookURL, message, embed, ics, data.ID+".ics", n.MaxDiscordRetries); err != nil {
+		return err
+	}
+	log.Println("Notification sent for event:", data.Summary)
+
+	rec := NotificationRecord{
+		RouteKey:         n.routeKey(),
+		EventID:          data.ID,
+		Updated:          data.Updated,
+		NotificationKind: rule.Kind(),
+		SentAt:           time.Now(),
+	}
+	if err := n.Store.Record(rec); err != nil {
+		return fmt.Errorf("unable to record notification: %w", err)
+	}
+	return nil
+}
+
+// routeKey は、このNotifierが担当するRouteの重複排除用識別子を返す。
+func (n *Notifier) routeKey() string {
+	if n.RouteKey != "" {
+		return n.RouteKey
+	}
+	return n.CalendarID + "|" + n.WebhookURL
+}
+
+// toEventData は、カレンダーイベントの開始・終了時刻を指定したタイムゾーンに変換し、
+// ルール評価に必要な情報をまとめて返す。開始時刻が取得できない場合はok=falseを返す。
+func toEventData(event *calendar.Event, location *time.Location) (eventData, bool) {
+	var startTime, endTime time.Time
+	var isAllDay bool
+	var err error
+
+	if event.Start.DateTime != "" {
+		startTime, err = time.Parse(time.RFC3339, event.Start.DateTime)
+		if err != nil {
+			log.Printf("Unable to parse event DateTime: %v", err)
+			return eventData{}, false
+		}
+		endTime, err = time.Parse(time.RFC3339, event.End.DateTime)
+		if err != nil {
+			log.Printf("Unable to parse event End DateTime: %v", err)
+			return eventData{}, false
+		}
+	} else if event.Start.Date != "" {
+		startTime, err = time.Parse("2006-01-02", event.Start.Date)
+		if err != nil {
+			log.Printf("Unable to parse event Start Date: %v", err)
+			return eventData{}, false
+		}
+		endTime, err = time.Parse("2006-01-02", event.End.Date)
+		if err != nil {
+			log.Printf("Unable to parse event End Date: %v", err)
+			return eventData{}, false
+		}
+		isAllDay = true
+	} else {
+		return eventData{}, false
+	}
+
+	return eventData{
+		ID:       event.Id,
+		Updated:  event.Updated,
+		Summary:  event.Summary,
+		Location: event.Location,
+		Start:    startTime.In(location),
+		End:      endTime.In(location),
+		IsAllDay: isAllDay,
+		Raw:      event,
+	}, true
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func testRule() ReminderRule {
+	return ReminderRule{Offset: 24 * time.Hour, Template: "{{.Summary}}"}
+}
+
+func testRuleWithMention(role string) ReminderRule {
+	return ReminderRule{Offset: 24 * time.Hour, Template: "{{.MentionRole}}{{.Summary}}", MentionRole: role}
+}
+
+func testEventData(id string) eventData {
+	start := time.Date(2026, 8, 1, 21, 0, 0, 0, time.UTC)
+	return eventData{
+		ID:      id,
+		Updated: "v1",
+		Summary: "raid",
+		Start:   start,
+		End:     start.Add(time.Hour),
+		Raw:     &calendar.Event{Id: id, Summary: "raid"},
+	}
+}
+
+func contentCapturingWebhook(t *testing.T) (*httptest.Server, *string) {
+	t.Helper()
+	var captured string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unable to parse multipart webhook body: %v", err)
+		}
+		var payload discordWebhookPayload
+		if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &payload); err != nil {
+			t.Errorf("unable to decode webhook payload: %v", err)
+		}
+		captured = payload.Content
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, &captured
+}
+
+func countingWebhook(t *testing.T) (*httptest.Server, *int64) {
+	t.Helper()
+	var hits int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestNotifierFireSkipsAlreadyNotified(t *testing.T) {
+	server, hits := countingWebhook(t)
+	store, err := NewJSONFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewJSONFileStore failed: %v", err)
+	}
+
+	n := &Notifier{WebhookURL: server.URL, Store: store, Location: time.UTC, RouteKey: "routeA"}
+	data := testEventData("event1")
+
+	if err := n.fire(testRule(), data); err != nil {
+		t.Fatalf("first fire returned error: %v", err)
+	}
+	if err := n.fire(testRule(), data); err != nil {
+		t.Fatalf("second fire returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt64(hits); got != 1 {
+		t.Errorf("webhook hit %d times, want exactly 1 (second fire should have been deduped)", got)
+	}
+}
+
+func TestNotifierFireResendsWhenUpdatedChanges(t *testing.T) {
+	server, hits := countingWebhook(t)
+	store, err := NewJSONFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewJSONFileStore failed: %v", err)
+	}
+
+	n := &Notifier{WebhookURL: server.URL, Store: store, Location: time.UTC, RouteKey: "routeA", ResendIfUpdated: true}
+	data := testEventData("event1")
+
+	if err := n.fire(testRule(), data); err != nil {
+		t.Fatalf("first fire returned error: %v", err)
+	}
+
+	data.Updated = "v2"
+	if err := n.fire(testRule(), data); err != nil {
+		t.Fatalf("second fire (after update) returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt64(hits); got != 2 {
+		t.Errorf("webhook hit %d times, want 2 (ResendIfUpdated should have resent after Updated changed)", got)
+	}
+}
+
+// TestNotifierFireDedupIsPerRoute is a regression test: two Notifiers for
+// different routes sharing one Store must not suppress each other just
+// because they're notifying about the same underlying event.
+func TestNotifierFireDedupIsPerRoute(t *testing.T) {
+	serverA, hitsA := countingWebhook(t)
+	serverB, hitsB := countingWebhook(t)
+	store, err := NewJSONFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewJSONFileStore failed: %v", err)
+	}
+
+	routeA := &Notifier{WebhookURL: serverA.URL, Store: store, Location: time.UTC, RouteKey: "calendarX|" + serverA.URL}
+	routeB := &Notifier{WebhookURL: serverB.URL, Store: store, Location: time.UTC, RouteKey: "calendarX|" + serverB.URL}
+	data := testEventData("shared-event")
+
+	if err := routeA.fire(testRule(), data); err != nil {
+		t.Fatalf("routeA fire returned error: %v", err)
+	}
+	if err := routeB.fire(testRule(), data); err != nil {
+		t.Fatalf("routeB fire returned error: %v", err)
+	}
+
+	if got := atomic.LoadInt64(hitsA); got != 1 {
+		t.Errorf("serverA hit %d times, want 1", got)
+	}
+	if got := atomic.LoadInt64(hitsB); got != 1 {
+		t.Errorf("serverB hit %d times, want 1 (must not be suppressed by routeA's dedup record)", got)
+	}
+}
+
+func TestNotifierFireMentionOverride(t *testing.T) {
+	tests := []struct {
+		name            string
+		mentionOverride string
+		want            string
+	}{
+		{name: "no override uses rule's own mention", mentionOverride: "", want: "@hereraid"},
+		{name: "override replaces rule's mention", mentionOverride: "@everyone", want: "@everyoneraid"},
+		{name: "MentionNone suppresses the mention", mentionOverride: MentionNone, want: "raid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, content := contentCapturingWebhook(t)
+			store, err := NewJSONFileStore(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatalf("NewJSONFileStore failed: %v", err)
+			}
+
+			n := &Notifier{WebhookURL: server.URL, Store: store, Location: time.UTC, RouteKey: "routeA", MentionOverride: tt.mentionOverride}
+			if err := n.fire(testRuleWithMention("@here"), testEventData("event1")); err != nil {
+				t.Fatalf("fire returned error: %v", err)
+			}
+
+			if *content != tt.want {
+				t.Errorf("webhook content = %q, want %q", *content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package notify builds the rich Discord notification content (embeds and
+// .ics calendar attachments) for a Google Calendar event. It is kept
+// separate from the root package so it can be unit tested without a
+// Calendar/Discord client.
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"google.golang.org/api/calendar/v3"
+)
+
+// embedColor is Discord's "blurple", used so generated embeds have a
+// consistent accent color.
+const embedColor = 0x5865F2
+
+// maxDescriptionRunes keeps descriptions well under Discord's 4096-rune
+// embed description limit, leaving room for the "..." suffix.
+const maxDescriptionRunes = 2048
+
+// BuildEmbed converts a Google Calendar event into a rich Discord embed:
+// title/description, start/end fields (in two timezones for timed events,
+// date only for all-day events), a Google Maps link
+// derived from the location, organizer/attendees, a conference link (Meet/
+// Zoom) when present, and a footer noting the recurrence rule.
+func BuildEmbed(event *calendar.Event, start, end time.Time, isAllDay bool, primary, secondary *time.Location) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       event.Summary,
+		URL:         event.HtmlLink,
+		Description: truncateDescription(event.Description),
+		Color:       embedColor,
+		Fields:      scheduleFields(start, end, isAllDay, primary, secondary),
+	}
+
+	if loc := strings.TrimSpace(event.Location); loc != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "場所",
+			Value: fmt.Sprintf("[%s](%s)", loc, mapsURL(loc)),
+		})
+	}
+
+	if people := peopleField(event); people != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "参加者",
+			Value: people,
+		})
+	}
+
+	if link := conferenceLink(event); link != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "会議リンク",
+			Value: fmt.Sprintf("[参加する](%s)", link),
+		})
+	}
+
+	if footer := recurrenceFooter(event); footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	}
+
+	return embed
+}
+
+// scheduleFields renders the start/end fields. For a timed event each field
+// shows the time in both the primary and secondary timezone; for an all-day
+// event it shows only the date in the primary timezone.
+func scheduleFields(start, end time.Time, isAllDay bool, primary, secondary *time.Location) []*discordgo.MessageEmbedField {
+	layout := "2006-01-02 15:04 MST"
+	if isAllDay {
+		layout = "2006-01-02"
+	}
+
+	format := func(t time.Time) string {
+		if isAllDay {
+			return t.In(primary).Format(layout)
+		}
+		return fmt.Sprintf("%s\n%s", t.In(primary).Format(layout), t.In(secondary).Format(layout))
+	}
+
+	return []*discordgo.MessageEmbedField{
+		{Name: "開始", Value: format(start), Inline: true},
+		{Name: "終了", Value: format(end), Inline: true},
+	}
+}
+
+// mapsURL builds a Google Maps search URL for a free-text location string.
+func mapsURL(location string) string {
+	return "https://www.google.com/maps/search/?api=1&query=" + url.QueryEscape(location)
+}
+
+// peopleField renders the organizer and attendee list as a single field
+// value, omitting whichever of the two is absent.
+func peopleField(event *calendar.Event) string {
+	var lines []string
+	if event.Organizer != nil {
+		lines = append(lines, "主催者: "+personLabel(event.Organizer.DisplayName, event.Organizer.Email))
+	}
+	if len(event.Attendees) > 0 {
+		names := make([]string, 0, len(event.Attendees))
+		for _, attendee := range event.Attendees {
+			names = append(names, personLabel(attendee.DisplayName, attendee.Email))
+		}
+		lines = append(lines, "参加者: "+strings.Join(names, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func personLabel(displayName, email string) string {
+	if displayName != "" {
+		return displayName
+	}
+	return email
+}
+
+// conferenceLink returns the first video entry point (Meet, Zoom, etc.)
+// attached to the event, if any.
+func conferenceLink(event *calendar.Event) string {
+	if event.ConferenceData == nil {
+		return ""
+	}
+	for _, entryPoint := range event.ConferenceData.EntryPoints {
+		if entryPoint.EntryPointType == "video" && entryPoint.Uri != "" {
+			return entryPoint.Uri
+		}
+	}
+	return ""
+}
+
+// recurrenceFooter summarizes the event's RRULE(s) for the embed footer.
+func recurrenceFooter(event *calendar.Event) string {
+	if len(event.Recurrence) == 0 {
+		return ""
+	}
+	return "繰り返し: " + strings.Join(event.Recurrence, "; ")
+}
+
+// markdownEscaper escapes characters that Discord interprets as markdown,
+// so event descriptions can't break the embed's formatting.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"~", `\~`,
+	"|", `\|`,
+	">", `\>`,
+)
+
+// truncateDescription escapes markdown in the description and truncates it
+// to maxDescriptionRunes, appending "..." when truncated.
+func truncateDescription(description string) string {
+	escaped := markdownEscaper.Replace(description)
+	runes := []rune(escaped)
+	if len(runes) <= maxDescriptionRunes {
+		return escaped
+	}
+	return string(runes[:maxDescriptionRunes]) + "..."
+}
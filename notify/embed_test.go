@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestBuildEmbedTimedEventShowsBothTimezones(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Date(2026, 8, 1, 21, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	event := &calendar.Event{Id: "evt1", Summary: "Workshop"}
+	embed := BuildEmbed(event, start, end, false, jst, time.UTC)
+
+	if embed.Fields[0].Name != "開始" || !strings.Contains(embed.Fields[0].Value, "06:00") {
+		t.Fatalf("expected start field to include JST time, got %q", embed.Fields[0].Value)
+	}
+	if !strings.Contains(embed.Fields[0].Value, "21:00") {
+		t.Fatalf("expected start field to include UTC time, got %q", embed.Fields[0].Value)
+	}
+}
+
+func TestBuildEmbedAllDayEventOmitsTime(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Date(2026, 8, 1, 0, 0, 0, 0, jst)
+	end := start.AddDate(0, 0, 1)
+
+	event := &calendar.Event{Id: "evt2", Summary: "Holiday"}
+	embed := BuildEmbed(event, start, end, true, jst, time.UTC)
+
+	if strings.Contains(embed.Fields[0].Value, ":") {
+		t.Fatalf("expected all-day start field to omit a clock time, got %q", embed.Fields[0].Value)
+	}
+}
+
+func TestBuildEmbedRecurringEventHasFooter(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Now()
+	event := &calendar.Event{
+		Id:         "evt3",
+		Summary:    "Weekly standup",
+		Recurrence: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"},
+	}
+
+	embed := BuildEmbed(event, start, start.Add(time.Hour), false, jst, time.UTC)
+
+	if embed.Footer == nil || !strings.Contains(embed.Footer.Text, "RRULE:FREQ=WEEKLY") {
+		t.Fatalf("expected footer to mention the recurrence rule, got %+v", embed.Footer)
+	}
+}
+
+func TestBuildEmbedNonRecurringEventHasNoFooter(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Now()
+	event := &calendar.Event{Id: "evt4", Summary: "One-off"}
+
+	embed := BuildEmbed(event, start, start.Add(time.Hour), false, jst, time.UTC)
+
+	if embed.Footer != nil {
+		t.Fatalf("expected no footer for a non-recurring event, got %+v", embed.Footer)
+	}
+}
+
+func TestBuildEmbedIncludesMapsLinkAndConferenceLink(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Now()
+	event := &calendar.Event{
+		Id:       "evt5",
+		Summary:  "Planning",
+		Location: "Tokyo Tower",
+		ConferenceData: &calendar.ConferenceData{
+			EntryPoints: []*calendar.EntryPoint{
+				{EntryPointType: "video", Uri: "https://meet.example.com/abc"},
+			},
+		},
+	}
+
+	embed := BuildEmbed(event, start, start.Add(time.Hour), false, jst, time.UTC)
+
+	var sawMaps, sawConference bool
+	for _, f := range embed.Fields {
+		if strings.Contains(f.Value, "google.com/maps") {
+			sawMaps = true
+		}
+		if strings.Contains(f.Value, "meet.example.com") {
+			sawConference = true
+		}
+	}
+	if !sawMaps {
+		t.Fatalf("expected a field with a Google Maps link, fields: %+v", embed.Fields)
+	}
+	if !sawConference {
+		t.Fatalf("expected a field with the conference link, fields: %+v", embed.Fields)
+	}
+}
+
+func TestTruncateDescriptionEscapesMarkdownAndTruncates(t *testing.T) {
+	desc := "*bold* " + strings.Repeat("x", maxDescriptionRunes+10)
+	got := truncateDescription(desc)
+
+	if !strings.HasPrefix(got, `\*bold\*`) {
+		t.Fatalf("expected markdown to be escaped, got prefix %q", got[:20])
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected truncated description to end with '...', got suffix %q", got[len(got)-10:])
+	}
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	icsTimestampLayout = "20060102T150405Z"
+	icsDateLayout      = "20060102"
+)
+
+// BuildICS renders a single-event iCalendar (RFC 5545) file so a
+// notification recipient can add the event to their own calendar with one
+// click. Only the fields needed for that are populated.
+func BuildICS(event *calendar.Event, start, end time.Time, isAllDay bool) []byte {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//reminder-for-discord//EN\r\n")
+	b.WriteString("BEGIN:VEVENT\r\n")
+	fmt.Fprintf(&b, "UID:%s@reminder-for-discord\r\n", event.Id)
+	fmt.Fprintf(&b, "DTSTAMP:%s\r\n", time.Now().UTC().Format(icsTimestampLayout))
+	if isAllDay {
+		fmt.Fprintf(&b, "DTSTART;VALUE=DATE:%s\r\n", start.Format(icsDateLayout))
+		fmt.Fprintf(&b, "DTEND;VALUE=DATE:%s\r\n", end.Format(icsDateLayout))
+	} else {
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", start.UTC().Format(icsTimestampLayout))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", end.UTC().Format(icsTimestampLayout))
+	}
+	fmt.Fprintf(&b, "SUMMARY:%s\r\n", icsEscape(event.Summary))
+	if event.Location != "" {
+		fmt.Fprintf(&b, "LOCATION:%s\r\n", icsEscape(event.Location))
+	}
+	if event.Description != "" {
+		fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", icsEscape(event.Description))
+	}
+	b.WriteString("END:VEVENT\r\n")
+	b.WriteString("END:VCALENDAR\r\n")
+	return []byte(b.String())
+}
+
+// icsEscape escapes text per RFC 5545 §3.3.11 so it is safe to embed in a
+// single ICS content line.
+func icsEscape(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
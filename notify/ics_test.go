@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestBuildICSTimedEventUsesUTCTimestamps(t *testing.T) {
+	start := time.Date(2026, 8, 1, 21, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	event := &calendar.Event{Id: "evt1", Summary: "Workshop", Location: "Online"}
+
+	ics := string(BuildICS(event, start, end, false))
+
+	if !strings.Contains(ics, "DTSTART:20260801T210000Z") {
+		t.Fatalf("expected DTSTART in UTC basic format, got:\n%s", ics)
+	}
+	if !strings.Contains(ics, "DTEND:20260801T220000Z") {
+		t.Fatalf("expected DTEND in UTC basic format, got:\n%s", ics)
+	}
+	if !strings.Contains(ics, "UID:evt1@reminder-for-discord") {
+		t.Fatalf("expected UID derived from event ID, got:\n%s", ics)
+	}
+}
+
+func TestBuildICSAllDayEventUsesDateOnly(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Date(2026, 8, 1, 0, 0, 0, 0, jst)
+	end := start.AddDate(0, 0, 1)
+	event := &calendar.Event{Id: "evt2", Summary: "Holiday"}
+
+	ics := string(BuildICS(event, start, end, true))
+
+	if !strings.Contains(ics, "DTSTART;VALUE=DATE:20260801") {
+		t.Fatalf("expected all-day DTSTART with VALUE=DATE, got:\n%s", ics)
+	}
+	if !strings.Contains(ics, "DTEND;VALUE=DATE:20260802") {
+		t.Fatalf("expected all-day DTEND with VALUE=DATE, got:\n%s", ics)
+	}
+}
+
+func TestBuildICSEscapesReservedCharacters(t *testing.T) {
+	start := time.Now()
+	event := &calendar.Event{
+		Id:          "evt3",
+		Summary:     "Team, sync; notes",
+		Description: "line one\nline two",
+	}
+
+	ics := string(BuildICS(event, start, start.Add(time.Hour), false))
+
+	if !strings.Contains(ics, `SUMMARY:Team\, sync\; notes`) {
+		t.Fatalf("expected summary to be escaped, got:\n%s", ics)
+	}
+	if !strings.Contains(ics, `DESCRIPTION:line one\nline two`) {
+		t.Fatalf("expected description newline to be escaped, got:\n%s", ics)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Route は、1つのGoogleカレンダーを1つのDiscordチャンネル（Webhook）に結びつける設定。
+// 1回の実行で複数のRouteを扱うことで、1デプロイで複数チーム・複数ギルドに配信できる。
+type Route struct {
+	CalendarID string `json:"calendar_id"`
+	WebhookURL string `json:"webhook_url"`
+	// ChannelMention は、このルートの通知に使うメンションでルール自身のMentionRoleを
+	// 上書きする。空文字列なら上書きしない（ルールの値をそのまま使う）。MentionNone
+	// ("none") を指定すると、ルールにMentionRoleがあってもこのルートではメンションを
+	// 付与しない。
+	ChannelMention string       `json:"channel_mention"`
+	Filter         FilterConfig `json:"filter"`
+}
+
+// LoadRoutes は、JSONファイルに定義された []Route を読み込む
+func LoadRoutes(path string) ([]Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read routes file: %w", err)
+	}
+
+	var routes []Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("unable to parse routes file: %w", err)
+	}
+	return routes, nil
+}
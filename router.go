@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/GoWorkshopConference/reminder-for-discord/calendarsvc"
+)
+
+// defaultRouteConcurrency は、Concurrencyが未設定の場合に同時実行するRoute数
+const defaultRouteConcurrency = 4
+
+// Router は、複数のRouteに対してNotifierを並行実行し、1つのRouteの失敗が
+// 他のRouteをブロックしないようにする。CalendarPoolから各ゴルーチンが自分専用の
+// クライアントを借りることで、1つの*calendar.Serviceを複数ゴルーチンで共有しない。
+type Router struct {
+	CalendarPool    *calendarsvc.CalendarPool
+	Routes          []Route
+	Rules           []ReminderRule
+	Store           Store
+	Location        *time.Location
+	ResendIfUpdated bool
+	TickInterval    time.Duration
+	// SecondaryLocation は、各Notifierのembedに併記する2つ目のタイムゾーン。
+	SecondaryLocation *time.Location
+	// Concurrency は、同時に処理するRouteの最大数。0以下の場合はdefaultRouteConcurrency。
+	Concurrency int
+	// GCMaxAge は、各NotifierのStore GCに使う保持期間。0以下の場合はNotifierの既定値を使う。
+	GCMaxAge time.Duration
+	// MaxDiscordRetries は、各Notifierが429を受け取った場合の最大リトライ回数。
+	// 0以下の場合はNotifierの既定値を使う。
+	MaxDiscordRetries int
+}
+
+// Run は、すべてのRouteに対して並行にNotifier.Runを実行する。
+// 個々のRouteのエラーはログに記録するのみで、他のRouteの処理は継続する。
+func (r *Router) Run(ctx context.Context) error {
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultRouteConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, route := range r.Routes {
+		route := route
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			filter, err := route.Filter.Compile()
+			if err != nil {
+				log.Printf("Error compiling filter for route %s: %v", route.CalendarID, err)
+				return
+			}
+
+			calendarClient, err := r.CalendarPool.Get(ctx)
+			if err != nil {
+				log.Printf("Error retrieving Calendar client for route %s: %v", route.CalendarID, err)
+				return
+			}
+			defer r.CalendarPool.Put(calendarClient)
+
+			notifier := &Notifier{
+				Calendar:          calendarClient,
+				CalendarID:        route.CalendarID,
+				WebhookURL:        route.WebhookURL,
+				Location:          r.Location,
+				Store:             r.Store,
+				ResendIfUpdated:   r.ResendIfUpdated,
+				Rules:             r.Rules,
+				TickInterval:      r.TickInterval,
+				Filter:            filter,
+				RouteKey:          route.CalendarID + "|" + route.WebhookURL,
+				MentionOverride:   route.ChannelMention,
+				SecondaryLocation: r.SecondaryLocation,
+				GCMaxAge:          r.GCMaxAge,
+				MaxDiscordRetries: r.MaxDiscordRetries,
+			}
+
+			if err := notifier.Run(ctx); err != nil {
+				log.Printf("Error running notifier for route %s: %v", route.CalendarID, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
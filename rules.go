@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+)
+
+// ReminderRule は、イベント開始の何前に、どのメッセージで通知するかを表す設定
+type ReminderRule struct {
+	// Offset は、イベント開始時刻からどれだけ前に通知するか（0なら開始時刻ちょうど）
+	Offset time.Duration `json:"offset"`
+	// Template は、通知メッセージのtext/templateテンプレート文字列
+	Template string `json:"template"`
+	// MentionRole は、通知に含めるメンション（"@here"、"@everyone"、"<@&roleID>"など）。空なら付与しない
+	MentionRole string `json:"mention_role"`
+}
+
+// reminderMessageData は、テンプレートに渡されるイベント情報
+type reminderMessageData struct {
+	Summary     string
+	Location    string
+	Start       string
+	End         string
+	IsAllDay    bool
+	MentionRole string
+}
+
+// Kind は、このルールによる通知をStoreで一意に識別するための通知種別を返す
+func (r ReminderRule) Kind() string {
+	return fmt.Sprintf("offset:%s", r.Offset)
+}
+
+// reminderRuleJSON は、設定ファイル上でOffsetを "168h" のような人間可読な文字列として
+// 書けるようにするための、ReminderRuleのJSON表現
+type reminderRuleJSON struct {
+	Offset      string `json:"offset"`
+	Template    string `json:"template"`
+	MentionRole string `json:"mention_role"`
+}
+
+// UnmarshalJSON は、Offsetをtime.ParseDurationで解釈する
+func (r *ReminderRule) UnmarshalJSON(data []byte) error {
+	var raw reminderRuleJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	offset, err := time.ParseDuration(raw.Offset)
+	if err != nil {
+		return fmt.Errorf("invalid offset %q: %w", raw.Offset, err)
+	}
+
+	r.Offset = offset
+	r.Template = raw.Template
+	r.MentionRole = raw.MentionRole
+	return nil
+}
+
+// MarshalJSON は、Offsetを "168h" のような人間可読な文字列として書き出す
+func (r ReminderRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(reminderRuleJSON{
+		Offset:      r.Offset.String(),
+		Template:    r.Template,
+		MentionRole: r.MentionRole,
+	})
+}
+
+// Render は、イベント情報を埋め込んだ通知メッセージを組み立てる
+func (r ReminderRule) Render(event eventData) (string, error) {
+	tmpl, err := template.New("reminder").Parse(r.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid template for rule %s: %w", r.Kind(), err)
+	}
+
+	data := reminderMessageData{
+		Summary:     event.Summary,
+		Location:    event.Location,
+		IsAllDay:    event.IsAllDay,
+		MentionRole: r.MentionRole,
+	}
+	if event.IsAllDay {
+		data.Start = event.Start.Format("2006-01-02")
+		data.End = event.End.Format("2006-01-02")
+	} else {
+		data.Start = event.Start.Format("2006-01-02 15:04")
+		data.End = event.End.Format("2006-01-02 15:04")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("unable to render template for rule %s: %w", r.Kind(), err)
+	}
+	return buf.String(), nil
+}
+
+// DefaultReminderRules は、設定ファイルが指定されなかった場合に使う既定のルールを返す。
+// 従来の「明日の予定を通知する」挙動と同じ文面で、各イベントの開始24時間前に通知する
+// 1件のルールとして表現している（翌日の暦日単位ではなく、開始時刻からの相対で判定する）。
+func DefaultReminderRules() []ReminderRule {
+	return []ReminderRule{
+		{
+			Offset:      24 * time.Hour,
+			MentionRole: "@here",
+			Template: "{{.MentionRole}}\n" +
+				"イベント名: {{.Summary}}\n" +
+				"場所: {{.Location}}\n" +
+				"{{if .IsAllDay}}終日イベント{{else}}開始時間: {{.Start}}\n終了時間: {{.End}}{{end}}",
+		},
+	}
+}
+
+// LoadReminderRules は、JSONファイルに定義された []ReminderRule を読み込む。
+// YAMLには対応していない（設定ファイルは常にJSONとして書くこと）。
+func LoadReminderRules(path string) ([]ReminderRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read reminder rules file: %w", err)
+	}
+
+	var rules []ReminderRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse reminder rules file: %w", err)
+	}
+	return rules, nil
+}
+
+// maxOffset は、ルール群の中で最大のOffsetを返す
+func maxOffset(rules []ReminderRule) time.Duration {
+	var max time.Duration
+	for _, rule := range rules {
+		if rule.Offset > max {
+			max = rule.Offset
+		}
+	}
+	return max
+}
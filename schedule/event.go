@@ -0,0 +1,34 @@
+package schedule
+
+import (
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// ToCalendarEvent normalizes a ParsedEvent into a *calendar.Event ready for
+// Events.Insert, stamping Start/End with an explicit TimeZone so the event
+// renders correctly regardless of the inserting client's local zone.
+func ToCalendarEvent(p ParsedEvent, location *time.Location) *calendar.Event {
+	event := &calendar.Event{
+		Summary: p.Summary,
+		Start: &calendar.EventDateTime{
+			DateTime: p.Start.Format(time.RFC3339),
+			TimeZone: location.String(),
+		},
+		End: &calendar.EventDateTime{
+			DateTime: p.End.Format(time.RFC3339),
+			TimeZone: location.String(),
+		},
+		Recurrence: p.Recurrence,
+	}
+
+	for _, attendee := range p.Attendees {
+		// @-mentions aren't necessarily email addresses; resolving a Discord
+		// user to their Calendar-notifiable email is out of scope here, so
+		// attendees are recorded by display name for now.
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{DisplayName: attendee})
+	}
+
+	return event
+}
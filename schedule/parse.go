@@ -0,0 +1,240 @@
+// Package schedule turns free-form scheduling text such as
+// "raid friday 9pm 2h @alice @bob" or "飲み会 明日21時 @bob" into a
+// normalized ParsedEvent, independently of the Google Calendar API so the
+// parsing rules can be unit-tested on their own.
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsedEvent is the normalized result of parsing scheduling text.
+type ParsedEvent struct {
+	Summary    string
+	Start      time.Time
+	End        time.Time
+	Attendees  []string
+	Recurrence []string // RRULE lines, e.g. "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+}
+
+var attendeeRe = regexp.MustCompile(`@[\w.+-]+`)
+
+var durationRe = regexp.MustCompile(`(?i)\b(\d+)h(\d+)m\b|\b(\d+)h\b|\b(\d+)m\b`)
+
+var (
+	ampmClockRe = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b`)
+	jaClockRe   = regexp.MustCompile(`(\d{1,2})時(?:(\d{1,2})分)?`)
+	hhmmClockRe = regexp.MustCompile(`\b([01]?\d|2[0-3]):([0-5]\d)\b`)
+)
+
+var (
+	jaNextWeekRe  = regexp.MustCompile(`来週([日月火水木金土])(?:曜日?)?`)
+	tomorrowRe    = regexp.MustCompile(`(?i)\btomorrow\b|明日`)
+	todayRe       = regexp.MustCompile(`(?i)\btoday\b|今日`)
+	nextWeekdayRe = regexp.MustCompile(`(?i)\bnext\s+(sunday|monday|tuesday|wednesday|thursday|friday|saturday|sun|mon|tues|tue|wed|thurs|thur|thu|fri|sat)\b`)
+	bareWeekdayRe = regexp.MustCompile(`(?i)\b(sunday|monday|tuesday|wednesday|thursday|friday|saturday|sun|mon|tues|tue|wed|thurs|thur|thu|fri|sat)\b`)
+)
+
+// Parse extracts attendees, a recurrence rule, a duration, a time of day,
+// and a date from text, treating whatever remains as the event title. now
+// and location anchor relative phrases ("tomorrow", "next fri", "来週金曜")
+// to the caller's current time and timezone.
+func Parse(text string, now time.Time, location *time.Location) (ParsedEvent, error) {
+	remaining := text
+
+	var attendees []string
+	attendees, remaining = extractAttendees(remaining)
+
+	var recurrence []string
+	recurrence, remaining = extractRecurrence(remaining)
+
+	var duration time.Duration
+	duration, remaining = extractDuration(remaining)
+
+	clock, hasClock, remaining := extractClock(remaining)
+	if !hasClock {
+		return ParsedEvent{}, fmt.Errorf("could not find a time of day in %q", text)
+	}
+
+	day, hasDate := extractDate(remaining, now, location)
+	remaining = day.remainder
+	if !hasDate {
+		day.date = now.In(location)
+	}
+
+	summary := strings.Join(strings.Fields(remaining), " ")
+	if summary == "" {
+		return ParsedEvent{}, fmt.Errorf("could not find an event title in %q", text)
+	}
+
+	start := time.Date(day.date.Year(), day.date.Month(), day.date.Day(), clock.hour, clock.minute, 0, 0, location)
+	if !hasDate && start.Before(now.In(location)) {
+		// A bare time with no date ("9pm" at 10pm today) means the next
+		// occurrence of that time, i.e. tomorrow.
+		start = start.AddDate(0, 0, 1)
+	}
+
+	if duration <= 0 {
+		duration = time.Hour
+	}
+
+	return ParsedEvent{
+		Summary:    summary,
+		Start:      start,
+		End:        start.Add(duration),
+		Attendees:  attendees,
+		Recurrence: recurrence,
+	}, nil
+}
+
+func extractAttendees(text string) ([]string, string) {
+	matches := attendeeRe.FindAllString(text, -1)
+	if len(matches) == 0 {
+		return nil, text
+	}
+	attendees := make([]string, len(matches))
+	for i, m := range matches {
+		attendees[i] = strings.TrimPrefix(m, "@")
+	}
+	return attendees, strings.TrimSpace(attendeeRe.ReplaceAllString(text, ""))
+}
+
+func extractDuration(text string) (time.Duration, string) {
+	loc := durationRe.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return 0, text
+	}
+
+	group := func(i int) string {
+		if loc[2*i] < 0 {
+			return ""
+		}
+		return text[loc[2*i]:loc[2*i+1]]
+	}
+
+	var d time.Duration
+	switch {
+	case group(1) != "":
+		h, _ := strconv.Atoi(group(1))
+		m, _ := strconv.Atoi(group(2))
+		d = time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	case group(3) != "":
+		h, _ := strconv.Atoi(group(3))
+		d = time.Duration(h) * time.Hour
+	case group(4) != "":
+		m, _ := strconv.Atoi(group(4))
+		d = time.Duration(m) * time.Minute
+	}
+
+	return d, removeSpan(text, loc[0], loc[1])
+}
+
+// clockTime is a parsed time of day, in the target location's wall clock.
+type clockTime struct {
+	hour, minute int
+}
+
+func extractClock(text string) (clockTime, bool, string) {
+	if loc := ampmClockRe.FindStringSubmatchIndex(text); loc != nil {
+		hour, _ := strconv.Atoi(text[loc[2]:loc[3]])
+		minute := 0
+		if loc[4] >= 0 {
+			minute, _ = strconv.Atoi(text[loc[4]:loc[5]])
+		}
+		hour = to24Hour(hour, strings.ToLower(text[loc[6]:loc[7]]))
+		return clockTime{hour, minute}, true, removeSpan(text, loc[0], loc[1])
+	}
+
+	if loc := jaClockRe.FindStringSubmatchIndex(text); loc != nil {
+		hour, _ := strconv.Atoi(text[loc[2]:loc[3]])
+		minute := 0
+		if loc[4] >= 0 {
+			minute, _ = strconv.Atoi(text[loc[4]:loc[5]])
+		}
+		return clockTime{hour, minute}, true, removeSpan(text, loc[0], loc[1])
+	}
+
+	if loc := hhmmClockRe.FindStringSubmatchIndex(text); loc != nil {
+		hour, _ := strconv.Atoi(text[loc[2]:loc[3]])
+		minute, _ := strconv.Atoi(text[loc[4]:loc[5]])
+		return clockTime{hour, minute}, true, removeSpan(text, loc[0], loc[1])
+	}
+
+	return clockTime{}, false, text
+}
+
+func to24Hour(hour int, meridiem string) int {
+	hour %= 12
+	if meridiem == "pm" {
+		hour += 12
+	}
+	return hour
+}
+
+// parsedDate is the result of extractDate: the resolved calendar date (time
+// of day is ignored) plus the text with the matched phrase removed.
+type parsedDate struct {
+	date      time.Time
+	remainder string
+}
+
+func extractDate(text string, now time.Time, location *time.Location) (parsedDate, bool) {
+	today := now.In(location)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, location)
+
+	if loc := jaNextWeekRe.FindStringSubmatchIndex(text); loc != nil {
+		if wd, ok := japaneseWeekdayKanji[text[loc[2]:loc[3]]]; ok {
+			return parsedDate{nextCalendarWeek(today, wd), removeSpan(text, loc[0], loc[1])}, true
+		}
+	}
+
+	if loc := tomorrowRe.FindStringIndex(text); loc != nil {
+		return parsedDate{today.AddDate(0, 0, 1), removeSpan(text, loc[0], loc[1])}, true
+	}
+
+	if loc := todayRe.FindStringIndex(text); loc != nil {
+		return parsedDate{today, removeSpan(text, loc[0], loc[1])}, true
+	}
+
+	if loc := nextWeekdayRe.FindStringSubmatchIndex(text); loc != nil {
+		if wd, ok := englishWeekdayNames[strings.ToLower(text[loc[2]:loc[3]])]; ok {
+			return parsedDate{nextOccurrence(today, wd, true), removeSpan(text, loc[0], loc[1])}, true
+		}
+	}
+
+	if loc := bareWeekdayRe.FindStringSubmatchIndex(text); loc != nil {
+		if wd, ok := englishWeekdayNames[strings.ToLower(text[loc[2]:loc[3]])]; ok {
+			return parsedDate{nextOccurrence(today, wd, false), removeSpan(text, loc[0], loc[1])}, true
+		}
+	}
+
+	return parsedDate{remainder: text}, false
+}
+
+// nextOccurrence returns the next date on/after `from` that falls on wd.
+// When forceNextWeek is true ("next friday"), a match on `from` itself
+// doesn't count and the following week's occurrence is returned instead.
+func nextOccurrence(from time.Time, wd time.Weekday, forceNextWeek bool) time.Time {
+	diff := (int(wd) - int(from.Weekday()) + 7) % 7
+	if diff == 0 && forceNextWeek {
+		diff = 7
+	}
+	return from.AddDate(0, 0, diff)
+}
+
+// nextCalendarWeek returns the date falling on wd in the week after
+// `from`'s week (Sunday-indexed, matching time.Weekday), for the Japanese
+// "来週〇曜" idiom, which always means the following calendar week
+// regardless of whether wd has already passed in the current week.
+func nextCalendarWeek(from time.Time, wd time.Weekday) time.Time {
+	diff := int(wd) - int(from.Weekday())
+	return from.AddDate(0, 0, diff+7)
+}
+
+func removeSpan(text string, start, end int) string {
+	return strings.TrimSpace(text[:start] + text[end:])
+}
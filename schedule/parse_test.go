@@ -0,0 +1,170 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// refNow is a fixed Wednesday so weekday-relative phrases are deterministic.
+var refNow = time.Date(2026, 7, 29, 10, 0, 0, 0, jst) // 2026-07-29 is a Wednesday
+
+func TestParseBasicPhraseWithDurationAndAttendees(t *testing.T) {
+	p, err := Parse("raid friday 9pm 2h @alice @bob", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if p.Summary != "raid" {
+		t.Errorf("Summary = %q, want %q", p.Summary, "raid")
+	}
+	if p.Start.Weekday() != time.Friday || p.Start.Hour() != 21 {
+		t.Errorf("Start = %v, want a Friday at 21:00", p.Start)
+	}
+	if got := p.End.Sub(p.Start); got != 2*time.Hour {
+		t.Errorf("duration = %v, want 2h", got)
+	}
+	if len(p.Attendees) != 2 || p.Attendees[0] != "alice" || p.Attendees[1] != "bob" {
+		t.Errorf("Attendees = %v, want [alice bob]", p.Attendees)
+	}
+}
+
+func TestParseTomorrowAndJapaneseTomorrow(t *testing.T) {
+	for _, text := range []string{"standup tomorrow 9am 30m", "スタンドアップ 明日 9am 30m"} {
+		p, err := Parse(text, refNow, jst)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", text, err)
+		}
+		wantDate := refNow.AddDate(0, 0, 1)
+		if p.Start.Year() != wantDate.Year() || p.Start.YearDay() != wantDate.YearDay() {
+			t.Errorf("Parse(%q).Start = %v, want tomorrow (%v)", text, p.Start, wantDate)
+		}
+		if p.Start.Hour() != 9 {
+			t.Errorf("Parse(%q).Start hour = %d, want 9", text, p.Start.Hour())
+		}
+	}
+}
+
+func TestParseNextWeekdayLandsOnUpcomingOccurrence(t *testing.T) {
+	// refNow is a Wednesday; "next fri" should land on the Friday two days
+	// away (this week's), since that Friday hasn't passed yet.
+	p, err := Parse("sync next fri 10:00 1h", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if p.Start.Weekday() != time.Friday {
+		t.Fatalf("Start weekday = %v, want Friday", p.Start.Weekday())
+	}
+	if days := p.Start.YearDay() - refNow.YearDay(); days != 2 {
+		t.Errorf("next fri is %d days away, want 2", days)
+	}
+}
+
+func TestParseNextWeekdayOnSameWeekdaySkipsToNextWeek(t *testing.T) {
+	// refNow is itself a Wednesday, so "next wed" must skip today and land
+	// 7 days later, unlike the bare "wed" which would mean today.
+	p, err := Parse("sync next wed 10:00 1h", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if days := p.Start.YearDay() - refNow.YearDay(); days != 7 {
+		t.Errorf("next wed is %d days away, want 7", days)
+	}
+}
+
+func TestParseJapaneseNextWeekWeekday(t *testing.T) {
+	// refNow is a Wednesday; this week's Friday is only 2 days away, but
+	// "来週金曜" (Friday of *next* week) must skip past it to 9 days away.
+	p, err := Parse("飲み会 来週金曜 21時 2h", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if p.Start.Weekday() != time.Friday {
+		t.Errorf("Start weekday = %v, want Friday", p.Start.Weekday())
+	}
+	if days := p.Start.YearDay() - refNow.YearDay(); days != 9 {
+		t.Errorf("来週金曜 resolved %d days away, want 9 (next week's Friday)", days)
+	}
+}
+
+func TestParseBareTimeWithNoDateRollsToNextDayIfPast(t *testing.T) {
+	// refNow is 10:00; "9am" has already passed today, so it should roll to
+	// tomorrow.
+	p, err := Parse("coffee chat 9am 30m", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	wantDate := refNow.AddDate(0, 0, 1)
+	if p.Start.YearDay() != wantDate.YearDay() {
+		t.Errorf("Start = %v, want tomorrow (%v)", p.Start, wantDate)
+	}
+}
+
+func TestParseEveryWeekdayRecurrence(t *testing.T) {
+	p, err := Parse("standup every weekday 9am 15m", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(p.Recurrence) != 1 || p.Recurrence[0] != "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR" {
+		t.Errorf("Recurrence = %v, want every-weekday RRULE", p.Recurrence)
+	}
+	if p.Summary != "standup" {
+		t.Errorf("Summary = %q, want %q", p.Summary, "standup")
+	}
+}
+
+func TestParseEveryFridayRecurrence(t *testing.T) {
+	p, err := Parse("retro every friday 17:00 1h", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(p.Recurrence) != 1 || p.Recurrence[0] != "RRULE:FREQ=WEEKLY;BYDAY=FR" {
+		t.Errorf("Recurrence = %v, want weekly Friday RRULE", p.Recurrence)
+	}
+}
+
+func TestParseJapaneseWeeklyRecurrence(t *testing.T) {
+	p, err := Parse("定例 毎週火曜 10時 1h", refNow, jst)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(p.Recurrence) != 1 || p.Recurrence[0] != "RRULE:FREQ=WEEKLY;BYDAY=TU" {
+		t.Errorf("Recurrence = %v, want weekly Tuesday RRULE", p.Recurrence)
+	}
+	if p.Summary != "定例" {
+		t.Errorf("Summary = %q, want %q", p.Summary, "定例")
+	}
+}
+
+func TestParseDurationFormats(t *testing.T) {
+	cases := []struct {
+		text string
+		want time.Duration
+	}{
+		{"call friday 9pm 90m", 90 * time.Minute},
+		{"call friday 9pm 1h30m", 90 * time.Minute},
+		{"call friday 9pm 2h", 2 * time.Hour},
+		{"call friday 9pm", time.Hour}, // no duration given: defaults to 1h
+	}
+	for _, c := range cases {
+		p, err := Parse(c.text, refNow, jst)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", c.text, err)
+		}
+		if got := p.End.Sub(p.Start); got != c.want {
+			t.Errorf("Parse(%q) duration = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestParseMissingTimeIsAnError(t *testing.T) {
+	if _, err := Parse("raid friday 2h", refNow, jst); err == nil {
+		t.Fatal("expected an error when no time of day is present")
+	}
+}
+
+func TestParseMissingSummaryIsAnError(t *testing.T) {
+	if _, err := Parse("friday 9pm 2h", refNow, jst); err == nil {
+		t.Fatal("expected an error when no title remains after parsing")
+	}
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// rruleByDay maps a time.Weekday to its RRULE BYDAY token.
+var rruleByDay = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// englishWeekdayNames maps the English weekday names and abbreviations this
+// parser recognizes to a time.Weekday.
+var englishWeekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// japaneseWeekdayKanji maps a single weekday kanji (as used in "火曜" or
+// "金曜日") to a time.Weekday.
+var japaneseWeekdayKanji = map[string]time.Weekday{
+	"日": time.Sunday,
+	"月": time.Monday,
+	"火": time.Tuesday,
+	"水": time.Wednesday,
+	"木": time.Thursday,
+	"金": time.Friday,
+	"土": time.Saturday,
+}
+
+var (
+	everyWeekdayRe      = regexp.MustCompile(`(?i)\bevery\s+weekday\b`)
+	everyDayNameRe      = regexp.MustCompile(`(?i)\bevery\s+(sunday|monday|tuesday|wednesday|thursday|friday|saturday|sun|mon|tues|tue|wed|thurs|thur|thu|fri|sat)\b`)
+	japaneseEveryWeekRe = regexp.MustCompile(`毎週([日月火水木金土])(?:曜日?)?`)
+)
+
+// extractRecurrence recognizes "every weekday" / "every friday" /
+// "毎週火曜" style phrases and returns the RRULE lines they imply, along
+// with the input text with the matched phrase removed.
+func extractRecurrence(text string) ([]string, string) {
+	if loc := everyWeekdayRe.FindStringIndex(text); loc != nil {
+		rule := "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+		return []string{rule}, removeSpan(text, loc[0], loc[1])
+	}
+
+	if loc := everyDayNameRe.FindStringSubmatchIndex(text); loc != nil {
+		name := strings.ToLower(text[loc[2]:loc[3]])
+		if wd, ok := englishWeekdayNames[name]; ok {
+			return []string{weeklyRRule(wd)}, removeSpan(text, loc[0], loc[1])
+		}
+	}
+
+	if loc := japaneseEveryWeekRe.FindStringSubmatchIndex(text); loc != nil {
+		kanji := text[loc[2]:loc[3]]
+		if wd, ok := japaneseWeekdayKanji[kanji]; ok {
+			return []string{weeklyRRule(wd)}, removeSpan(text, loc[0], loc[1])
+		}
+	}
+
+	return nil, text
+}
+
+func weeklyRRule(wd time.Weekday) string {
+	return fmt.Sprintf("RRULE:FREQ=WEEKLY;BYDAY=%s", rruleByDay[wd])
+}
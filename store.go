@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// NotificationRecord は、どのイベント・どのルート・どのタイミングで通知を送ったかを表す記録
+type NotificationRecord struct {
+	RouteKey         string    `json:"route_key"`
+	EventID          string    `json:"event_id"`
+	Updated          string    `json:"updated"`
+	NotificationKind string    `json:"notification_kind"`
+	SentAt           time.Time `json:"sent_at"`
+}
+
+// key はレコードを一意に識別するためのキーを返す。RouteKeyを含めるのは、同じ
+// カレンダーを複数のRouteで配信する場合に、あるRouteの送信が他のRouteの
+// 重複排除に巻き込まれて通知漏れを起こさないようにするため。
+func (r NotificationRecord) key() string {
+	return r.RouteKey + "|" + r.EventID + "|" + r.NotificationKind
+}
+
+// Store は、送信済み通知の記録を永続化するためのインターフェース。
+// デフォルトではローカルJSONファイルを使うが、Firestore/Redisなど
+// 他のバックエンドに差し替えられるようにしている。
+type Store interface {
+	// Has は、指定したルート・イベント・通知種別について、既に通知済みかどうかを返す。
+	// resendIfUpdated が true の場合、updated が記録と異なっていれば未送信扱いにする。
+	// routeKey は、同じイベントを複数のRouteに配信する際に重複排除の記録が
+	// 混ざらないようにするための、Route単位の識別子。
+	Has(routeKey, eventID, notificationKind, updated string, resendIfUpdated bool) (bool, error)
+	// Record は、通知が送信済みであることを記録する。
+	Record(rec NotificationRecord) error
+	// GC は、sentAt が指定した保持期間より古いレコードを削除する。
+	GC(maxAge time.Duration) error
+}
+
+// JSONFileStore は、送信済み通知の記録をローカルのJSONファイルに保存するStore実装
+type JSONFileStore struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]NotificationRecord
+}
+
+// NewJSONFileStore は、path にあるJSONファイルから状態を読み込んでStoreを作る。
+// ファイルが存在しない場合は空の状態から開始する。
+func NewJSONFileStore(path string) (*JSONFileStore, error) {
+	s := &JSONFileStore{
+		path:    path,
+		records: make(map[string]NotificationRecord),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	var recs []NotificationRecord
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, err
+	}
+	for _, rec := range recs {
+		s.records[rec.key()] = rec
+	}
+	return s, nil
+}
+
+func (s *JSONFileStore) Has(routeKey, eventID, notificationKind, updated string, resendIfUpdated bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[routeKey+"|"+eventID+"|"+notificationKind]
+	if !ok {
+		return false, nil
+	}
+	if resendIfUpdated && rec.Updated != updated {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *JSONFileStore) Record(rec NotificationRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[rec.key()] = rec
+	return s.save()
+}
+
+func (s *JSONFileStore) GC(maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxAge)
+	for k, rec := range s.records {
+		if rec.SentAt.Before(cutoff) {
+			delete(s.records, k)
+		}
+	}
+	return s.save()
+}
+
+// save はロック済みの状態で呼び出される前提で、現在のレコードをJSONファイルに書き出す
+func (s *JSONFileStore) save() error {
+	recs := make([]NotificationRecord, 0, len(s.records))
+	for _, rec := range s.records {
+		recs = append(recs, rec)
+	}
+
+	data, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
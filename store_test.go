@@ -0,0 +1,151 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *JSONFileStore {
+	t.Helper()
+	store, err := NewJSONFileStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("NewJSONFileStore failed: %v", err)
+	}
+	return store
+}
+
+func TestJSONFileStoreHasRecord(t *testing.T) {
+	store := newTestStore(t)
+
+	has, err := store.Has("routeA", "event1", "offset:24h0m0s", "v1", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if has {
+		t.Fatal("Has = true before any Record, want false")
+	}
+
+	rec := NotificationRecord{RouteKey: "routeA", EventID: "event1", Updated: "v1", NotificationKind: "offset:24h0m0s", SentAt: time.Now()}
+	if err := store.Record(rec); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	has, err = store.Has("routeA", "event1", "offset:24h0m0s", "v1", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if !has {
+		t.Fatal("Has = false after Record, want true")
+	}
+}
+
+func TestJSONFileStoreHasIsNamespacedByRoute(t *testing.T) {
+	// Regression test for a bug where two routes matching the same event
+	// shared a single dedup record: recording for one route silently
+	// suppressed notification for every other route watching that event.
+	store := newTestStore(t)
+
+	rec := NotificationRecord{RouteKey: "routeA", EventID: "event1", Updated: "v1", NotificationKind: "offset:24h0m0s", SentAt: time.Now()}
+	if err := store.Record(rec); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	has, err := store.Has("routeB", "event1", "offset:24h0m0s", "v1", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if has {
+		t.Fatal("Has(routeB) = true after only routeA recorded, want false")
+	}
+
+	has, err = store.Has("routeA", "event1", "offset:24h0m0s", "v1", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if !has {
+		t.Fatal("Has(routeA) = false, want true")
+	}
+}
+
+func TestJSONFileStoreResendIfUpdated(t *testing.T) {
+	store := newTestStore(t)
+
+	rec := NotificationRecord{RouteKey: "routeA", EventID: "event1", Updated: "v1", NotificationKind: "offset:24h0m0s", SentAt: time.Now()}
+	if err := store.Record(rec); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	has, err := store.Has("routeA", "event1", "offset:24h0m0s", "v2", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if !has {
+		t.Fatal("Has with resendIfUpdated=false and a changed Updated = false, want true")
+	}
+
+	has, err = store.Has("routeA", "event1", "offset:24h0m0s", "v2", true)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if has {
+		t.Fatal("Has with resendIfUpdated=true and a changed Updated = true, want false")
+	}
+}
+
+func TestJSONFileStoreGC(t *testing.T) {
+	store := newTestStore(t)
+
+	old := NotificationRecord{RouteKey: "routeA", EventID: "old-event", NotificationKind: "offset:24h0m0s", SentAt: time.Now().Add(-40 * 24 * time.Hour)}
+	recent := NotificationRecord{RouteKey: "routeA", EventID: "recent-event", NotificationKind: "offset:24h0m0s", SentAt: time.Now().Add(-1 * time.Hour)}
+	if err := store.Record(old); err != nil {
+		t.Fatalf("Record(old) returned error: %v", err)
+	}
+	if err := store.Record(recent); err != nil {
+		t.Fatalf("Record(recent) returned error: %v", err)
+	}
+
+	if err := store.GC(30 * 24 * time.Hour); err != nil {
+		t.Fatalf("GC returned error: %v", err)
+	}
+
+	has, err := store.Has("routeA", "old-event", "offset:24h0m0s", "", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if has {
+		t.Fatal("Has(old-event) = true after GC, want the old record pruned")
+	}
+
+	has, err = store.Has("routeA", "recent-event", "offset:24h0m0s", "", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if !has {
+		t.Fatal("Has(recent-event) = false after GC, want the recent record kept")
+	}
+}
+
+func TestNewJSONFileStoreReloadsPersistedRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := NewJSONFileStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONFileStore failed: %v", err)
+	}
+	rec := NotificationRecord{RouteKey: "routeA", EventID: "event1", NotificationKind: "offset:24h0m0s", SentAt: time.Now()}
+	if err := store.Record(rec); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	reloaded, err := NewJSONFileStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONFileStore (reload) failed: %v", err)
+	}
+	has, err := reloaded.Has("routeA", "event1", "offset:24h0m0s", "", false)
+	if err != nil {
+		t.Fatalf("Has returned error: %v", err)
+	}
+	if !has {
+		t.Fatal("Has = false after reload, want the persisted record to survive a restart")
+	}
+}
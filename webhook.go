@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxDiscordRetries は、maxRetriesに0以下の値が渡された場合に使う既定の最大リトライ回数
+const maxDiscordRetries = 3
+
+// discordWebhookPayload は、Webhook実行リクエストの "payload_json" パートの形。
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+type discordWebhookPayload struct {
+	Content string                    `json:"content,omitempty"`
+	Embeds  []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+}
+
+// DiscordのWebhookに通知を送信。embedが指定されていればメッセージのembedとして、
+// icsAttachmentが指定されていればmultipart/form-dataのファイルとして添付する。
+// 429（レート制限）を受け取った場合はRetry-Afterヘッダに従って待機のうえリトライする。
+// maxRetriesが0以下の場合はmaxDiscordRetriesを使う。
+func sendDiscordNotification(webhookURL, content string, embed *discordgo.MessageEmbed, icsAttachment []byte, icsFilename string, maxRetries int) error {
+	if maxRetries <= 0 {
+		maxRetries = maxDiscordRetries
+	}
+
+	payload := discordWebhookPayload{Content: content}
+	if embed != nil {
+		payload.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, contentType, err := buildWebhookBody(payloadBytes, icsAttachment, icsFilename)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.Post(webhookURL, contentType, body)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			return nil
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			time.Sleep(wait)
+			continue
+		}
+
+		status := resp.Status
+		resp.Body.Close()
+		return fmt.Errorf("failed to send notification: %v", status)
+	}
+}
+
+// buildWebhookBody は、Discord Webhookへ送るリクエストボディを組み立てる。
+// icsAttachmentがnilならJSONのみ、そうでなければ "payload_json" フィールドと
+// "files[0]" フィールドを持つmultipart/form-dataを返す。
+func buildWebhookBody(payloadBytes, icsAttachment []byte, icsFilename string) (*bytes.Buffer, string, error) {
+	if icsAttachment == nil {
+		return bytes.NewBuffer(payloadBytes), "application/json", nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	if err := writer.WriteField("payload_json", string(payloadBytes)); err != nil {
+		return nil, "", err
+	}
+
+	part, err := writer.CreateFormFile("files[0]", icsFilename)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(icsAttachment); err != nil {
+		return nil, "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// retryAfter は、DiscordのRetry-Afterヘッダ（秒数）を待機時間に変換する。
+// ヘッダが無い・解釈できない場合は1秒を返す。
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return time.Second
+	}
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
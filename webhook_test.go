@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSendDiscordNotificationRetriesOn429(t *testing.T) {
+	var attempts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&attempts, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	if err := sendDiscordNotification(server.URL, "hello", nil, nil, "", 3); err != nil {
+		t.Fatalf("sendDiscordNotification returned error: %v", err)
+	}
+	if got := atomic.LoadInt64(&attempts); got != 3 {
+		t.Errorf("server received %d attempts, want 3 (2 failed + 1 successful)", got)
+	}
+}
+
+func TestSendDiscordNotificationGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&attempts, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	err := sendDiscordNotification(server.URL, "hello", nil, nil, "", 2)
+	if err == nil {
+		t.Fatal("sendDiscordNotification returned no error after exhausting retries, want an error")
+	}
+	if got := atomic.LoadInt64(&attempts); got != 3 {
+		t.Errorf("server received %d attempts, want 3 (1 initial + 2 retries)", got)
+	}
+}